@@ -1,31 +1,61 @@
 package main
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"golang.org/x/sys/windows/registry"
 )
 
 type Config struct {
-	Binaries []*Binary `json:"binaries"`
-	BinDir   string    `json:"binDir"`
+	Binaries    []*Binary `json:"binaries"`
+	BinDir      string    `json:"binDir"`
+	Parallelism int       `json:"parallelism"`
 }
 
 type Binary struct {
-	Package   string `json:"package"`
-	Version   string `json:"version"`
-	Name      string `json:"name"`
-	Toolchain string `json:"toolchain"`
+	Package   string   `json:"package"`
+	Version   string   `json:"version"`
+	Name      string   `json:"name"`
+	Toolchain string   `json:"toolchain"`
+	Targets   []string `json:"targets"`
 }
 
 const configFileName = "bd.json"
+const lockFileName = "bd.lock"
+
+// LockFile records, for every installed binary, the exact module version and
+// content hashes that were resolved the last time `bd install` ran, so that
+// later installs can detect drift instead of silently picking up a different
+// build.
+type LockFile struct {
+	Binaries map[string]*LockEntry `json:"binaries"`
+}
+
+type LockEntry struct {
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	ModuleHash   string `json:"moduleHash"`
+	FileName     string `json:"fileName"`
+	BinarySHA256 string `json:"binarySha256"`
+}
 
 var isWindowsDevModeEnabled = false
 
@@ -56,10 +86,23 @@ func main() {
 		printUsageAndExit()
 	}
 
-	config, err := loadConfig()
+	// Extraction is how a bd.json gets onto a fresh machine in the first
+	// place, so it must not require one to already exist.
+	if os.Args[1] == "bundle" && len(os.Args) > 2 && os.Args[2] == "--extract" {
+		if len(os.Args) < 4 {
+			printUsageAndExit()
+		}
+		if err := extractBundle(os.Args[3], extractionBinDir()); err != nil {
+			die(fmt.Sprintf("Failed to extract bundle: %v", err))
+		}
+		return
+	}
+
+	config, configPath, err := loadConfig()
 	if err != nil {
 		die(fmt.Sprintf("Failed to load %s: %v", configFileName, err))
 	}
+	configDir := filepath.Dir(configPath)
 
 	binDir, err := filepath.Abs(config.BinDir)
 	if err != nil {
@@ -67,9 +110,26 @@ func main() {
 	}
 
 	clean := false
+	update := false
+	targetOverride := ""
+	jobs := 0
 	for _, arg := range os.Args[2:] {
-		if arg == "--clean" || arg == "-clean" || arg == "-c" {
+		if arg == "--" {
+			break
+		}
+		switch {
+		case arg == "--clean" || arg == "-clean" || arg == "-c":
 			clean = true
+		case arg == "--update":
+			update = true
+		case strings.HasPrefix(arg, "--target="):
+			targetOverride = strings.TrimPrefix(arg, "--target=")
+		case strings.HasPrefix(arg, "--jobs="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "--jobs="))
+			if err != nil || n <= 0 {
+				die(fmt.Sprintf("invalid --jobs value %q", arg))
+			}
+			jobs = n
 		}
 	}
 	if clean {
@@ -77,10 +137,19 @@ func main() {
 			die(fmt.Sprintf("failed to clean %s: %v", binDir, err))
 		}
 	}
+	if jobs == 0 {
+		if config.Parallelism < 0 {
+			die(fmt.Sprintf("invalid parallelism value %d in %s", config.Parallelism, configFileName))
+		}
+		jobs = config.Parallelism
+	}
+	if jobs == 0 {
+		jobs = runtime.NumCPU()
+	}
 
 	switch os.Args[1] {
 	case "install":
-		if err := installBinaries(config, binDir); err != nil {
+		if err := installBinaries(config, binDir, configDir, update, targetOverride, jobs); err != nil {
 			die(fmt.Sprintf("Failed to install binaries: %v", err))
 		}
 	case "exec":
@@ -88,13 +157,39 @@ func main() {
 			printUsageAndExit()
 		}
 		execBinary(config, binDir, os.Args[2], os.Args[3:])
+	case "verify":
+		if err := verifyBinaries(binDir, configDir); err != nil {
+			die(fmt.Sprintf("Verification failed: %v", err))
+		}
+	case "shell":
+		shellCmd(binDir)
+	case "run":
+		runArgs := os.Args[2:]
+		if len(runArgs) > 0 && runArgs[0] == "--" {
+			runArgs = runArgs[1:]
+		}
+		if len(runArgs) == 0 {
+			printUsageAndExit()
+		}
+		runCmd(binDir, runArgs)
+	case "bundle":
+		bundleArgs := os.Args[2:]
+		outPath := defaultBundlePath()
+		for _, arg := range bundleArgs {
+			if strings.HasPrefix(arg, "--out=") {
+				outPath = strings.TrimPrefix(arg, "--out=")
+			}
+		}
+		if err := createBundle(binDir, configDir, outPath); err != nil {
+			die(fmt.Sprintf("Failed to create bundle: %v", err))
+		}
 	default:
 		printUsageAndExit()
 	}
 }
 
 func printUsageAndExit() {
-	fmt.Println("Usage: bd <install|exec>")
+	fmt.Println("Usage: bd <install|exec|verify|shell|run|bundle>")
 	os.Exit(1)
 }
 
@@ -103,28 +198,79 @@ func die(msg string) {
 	os.Exit(1)
 }
 
-func loadConfig() (*Config, error) {
-	file, err := os.ReadFile(configFileName)
+// loadConfig locates and parses bd.json, returning the config alongside the
+// path it was loaded from so callers can resolve bd.lock and other sibling
+// files relative to it rather than the invocation directory.
+func loadConfig() (*Config, string, error) {
+	configPath, err := findConfigFile()
 	if err != nil {
-		return nil, fmt.Errorf("read %s: %v", configFileName, err)
+		return nil, "", err
+	}
+
+	file, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("read %s: %v", configPath, err)
 	}
 
 	var config Config
 	if err := json.Unmarshal(file, &config); err != nil {
-		return nil, fmt.Errorf("unmarshal %s: %v", configFileName, err)
+		return nil, "", fmt.Errorf("unmarshal %s: %v", configPath, err)
 	}
 
 	if config.BinDir == "" {
 		config.BinDir = "bin"
 	}
+	if !filepath.IsAbs(config.BinDir) {
+		config.BinDir = filepath.Join(filepath.Dir(configPath), config.BinDir)
+	}
 
 	for _, bin := range config.Binaries {
 		if err := normalizeBinary(bin); err != nil {
-			return nil, fmt.Errorf("normalize %v: %v", bin, err)
+			return nil, "", fmt.Errorf("normalize %v: %v", bin, err)
+		}
+	}
+
+	return &config, configPath, nil
+}
+
+// findConfigFile locates bd.json. BD_CONFIG, if set, is used verbatim.
+// Otherwise it walks up from the current directory the way the go tool
+// locates go.mod, stopping at the filesystem root or at the first go.mod
+// found without a sibling bd.json, so `bd` works from any subdirectory of a
+// project without picking up an unrelated bd.json further up the tree.
+func findConfigFile() (string, error) {
+	if override := os.Getenv("BD_CONFIG"); override != "" {
+		if _, err := os.Stat(override); err != nil {
+			return "", fmt.Errorf("BD_CONFIG=%s: %w", override, err)
 		}
+		abs, err := filepath.Abs(override)
+		if err != nil {
+			return "", fmt.Errorf("resolve BD_CONFIG=%s: %w", override, err)
+		}
+		return abs, nil
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("getwd: %w", err)
 	}
+	start := dir
 
-	return &config, nil
+	for {
+		candidate := filepath.Join(dir, configFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "", fmt.Errorf("no %s found walking up from %s (stopped at go.mod in %s)", configFileName, start, dir)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in %s or any parent directory", configFileName, start)
+		}
+		dir = parent
+	}
 }
 
 func normalizeBinary(bin *Binary) error {
@@ -141,33 +287,154 @@ func normalizeBinary(bin *Binary) error {
 	return nil
 }
 
-func installBinaries(config *Config, binDir string) error {
+// installBinaries installs every binary in config concurrently across a
+// bounded worker pool. Each worker writes go install's output into its own
+// buffer so interleaved builds can't corrupt the terminal; a single failure
+// is recorded and reported via errors.Join without blocking the rest of the
+// pool.
+func installBinaries(config *Config, binDir, configDir string, update bool, targetOverride string, jobs int) error {
 	if err := os.MkdirAll(binDir, 0755); err != nil {
 		return fmt.Errorf("create binDir: %w", err)
 	}
 
-	for _, bin := range config.Binaries {
-		if err := installBinary(*bin, binDir); err != nil {
-			return fmt.Errorf("install binary %s: %w", bin.Name, err)
-		}
+	lock, err := loadLockFile(configDir)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", lockFileName, err)
+	}
+
+	total := len(config.Binaries)
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	var lockMu sync.Mutex  // guards lock.Binaries
+	var printMu sync.Mutex // serializes buffer flushes and the done counter
+	var errs []error
+	done := 0
+
+	for _, b := range config.Binaries {
+		bin := *b
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var out bytes.Buffer
+			err := installBinaryTargets(bin, binDir, lock, &lockMu, update, targetOverride, &out)
+
+			printMu.Lock()
+			defer printMu.Unlock()
+			_, _ = os.Stdout.Write(out.Bytes())
+			done++
+			if err != nil {
+				errs = append(errs, fmt.Errorf("install binary %s: %w", bin.Name, err))
+				return
+			}
+			fmt.Printf("[%d/%d] installed %s %s\n", done, total, bin.Name, bin.Version)
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if err := saveLockFile(configDir, lock); err != nil {
+		return fmt.Errorf("write %s: %w", lockFileName, err)
 	}
 
 	fmt.Printf("\nAll binaries installed in %s\n", binDir)
 	return nil
 }
 
-func installBinary(bin Binary, binDir string) error {
-	finalPath := filepath.Join(binDir, buildBinName(bin.Name, bin.Version, bin.Toolchain))
-	symlinkPath := filepath.Join(binDir, buildSymlinkName(bin.Name))
+// installBinaryTargets installs bin for every one of its configured targets
+// (or just the host platform if none are set), writing all output for bin
+// into out so the caller can flush it atomically.
+func installBinaryTargets(bin Binary, binDir string, lock *LockFile, lockMu *sync.Mutex, update bool, targetOverride string, out *bytes.Buffer) error {
+	targets := bin.Targets
+	if targetOverride != "" {
+		targets = []string{targetOverride}
+	}
+
+	if len(targets) == 0 {
+		return installBinary(bin, binDir, lock, lockMu, update, "", "", out)
+	}
+
+	for _, target := range targets {
+		goos, goarch, err := parseTarget(target)
+		if err != nil {
+			return err
+		}
+		if err := installBinary(bin, binDir, lock, lockMu, update, goos, goarch, out); err != nil {
+			return fmt.Errorf("target %s: %w", target, err)
+		}
+	}
+	return nil
+}
+
+// parseTarget splits a "GOOS/GOARCH" pair such as "linux/arm64".
+func parseTarget(target string) (goos, goarch string, err error) {
+	parts := strings.SplitN(target, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid target %q, expected GOOS/GOARCH", target)
+	}
+	return parts[0], parts[1], nil
+}
+
+// installBinary builds bin for a single target. goos/goarch are empty to
+// build for the host platform using the ambient toolchain defaults; a
+// symlink is only created when the target matches the current host, since
+// that's the only build directly runnable on this machine. All status output
+// and go install's own output are written to out rather than os.Stdout so
+// concurrent callers can flush it atomically. lockMu guards concurrent
+// access to lock.Binaries.
+func installBinary(bin Binary, binDir string, lock *LockFile, lockMu *sync.Mutex, update bool, goos, goarch string, out *bytes.Buffer) error {
+	finalPath := filepath.Join(binDir, buildBinName(bin.Name, bin.Version, bin.Toolchain, goos, goarch))
+	isHostTarget := goos == "" || (goos == runtime.GOOS && goarch == runtime.GOARCH)
+	lockKey := bin.Name
+	if goos != "" {
+		lockKey = fmt.Sprintf("%s-%s-%s", bin.Name, goos, goarch)
+	}
 
 	printVersion := bin.Version
 	if bin.Toolchain != "" {
 		printVersion += fmt.Sprintf(" (%s)", bin.Toolchain)
 	}
+	if goos != "" {
+		printVersion += fmt.Sprintf(" [%s/%s]", goos, goarch)
+	}
 
 	if _, err := os.Stat(finalPath); err == nil && bin.Version != "latest" {
-		fmt.Printf("Already installed: %s %s\n", bin.Name, printVersion)
-		return symlinkBinary(finalPath, symlinkPath)
+		lockMu.Lock()
+		existing, ok := lock.Binaries[lockKey]
+		lockMu.Unlock()
+		if ok && !update {
+			binarySHA256, err := sha256File(finalPath)
+			if err != nil {
+				return fmt.Errorf("hash binary: %w", err)
+			}
+			if existing.BinarySHA256 != binarySHA256 {
+				return fmt.Errorf("locked checksum mismatch for %s: locked %s, found %s; rerun with --update to accept the change",
+					lockKey, existing.BinarySHA256, binarySHA256)
+			}
+		} else {
+			resolvedVersion, moduleHash, err := readModuleInfo(finalPath)
+			if err != nil {
+				return fmt.Errorf("read build info: %w", err)
+			}
+			binarySHA256, err := sha256File(finalPath)
+			if err != nil {
+				return fmt.Errorf("hash binary: %w", err)
+			}
+			lockMu.Lock()
+			lock.Binaries[lockKey] = &LockEntry{Package: bin.Package, Version: resolvedVersion, ModuleHash: moduleHash,
+				FileName: filepath.Base(finalPath), BinarySHA256: binarySHA256}
+			lockMu.Unlock()
+		}
+		fmt.Fprintf(out, "Already installed: %s %s\n", bin.Name, printVersion)
+		if isHostTarget {
+			return symlinkBinary(finalPath, filepath.Join(binDir, buildSymlinkName(bin.Name)))
+		}
+		return nil
 	}
 
 	tempDir, err := os.MkdirTemp("", "bd-build")
@@ -181,7 +448,10 @@ func installBinary(bin Binary, binDir string) error {
 	if bin.Toolchain != "" {
 		cmd.Env = append(cmd.Env, "GOTOOLCHAIN="+bin.Toolchain)
 	}
-	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if goos != "" {
+		cmd.Env = append(cmd.Env, "GOOS="+goos, "GOARCH="+goarch)
+	}
+	cmd.Stdout, cmd.Stderr = out, out
 
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("go install %s: %w", bin.Package, err)
@@ -193,19 +463,475 @@ func installBinary(bin Binary, binDir string) error {
 	}
 
 	binaryPath := filepath.Join(tempDir, files[0].Name())
+
+	resolvedVersion, moduleHash, err := readModuleInfo(binaryPath)
+	if err != nil {
+		return fmt.Errorf("read build info: %w", err)
+	}
+
+	binarySHA256, err := sha256File(binaryPath)
+	if err != nil {
+		return fmt.Errorf("hash binary: %w", err)
+	}
+
+	lockMu.Lock()
+	existing, ok := lock.Binaries[lockKey]
+	lockMu.Unlock()
+	if ok && !update {
+		if existing.Version != resolvedVersion || existing.BinarySHA256 != binarySHA256 {
+			return fmt.Errorf("locked checksum mismatch for %s: locked %s (%s), resolved %s (%s); rerun with --update to accept the change",
+				lockKey, existing.Version, existing.BinarySHA256, resolvedVersion, binarySHA256)
+		}
+	}
+
 	if err := os.Rename(binaryPath, finalPath); err != nil {
 		return fmt.Errorf("move binary to final path: %w", err)
 	}
 
-	if err := symlinkBinary(finalPath, symlinkPath); err != nil {
-		return fmt.Errorf("symlink binary: %w", err)
+	if isHostTarget {
+		if err := symlinkBinary(finalPath, filepath.Join(binDir, buildSymlinkName(bin.Name))); err != nil {
+			return fmt.Errorf("symlink binary: %w", err)
+		}
+	}
+
+	lockMu.Lock()
+	lock.Binaries[lockKey] = &LockEntry{
+		Package:      bin.Package,
+		Version:      resolvedVersion,
+		ModuleHash:   moduleHash,
+		FileName:     filepath.Base(finalPath),
+		BinarySHA256: binarySHA256,
+	}
+	lockMu.Unlock()
+
+	fmt.Fprintf(out, "Installed: %s %s\n", bin.Name, printVersion)
+
+	return nil
+}
+
+// verifyBinaries re-hashes every binary recorded in bd.lock and reports any
+// file that is missing or whose content no longer matches what was installed.
+func verifyBinaries(binDir, configDir string) error {
+	lock, err := loadLockFile(configDir)
+	if err != nil {
+		return fmt.Errorf("load %s: %w", lockFileName, err)
+	}
+	if len(lock.Binaries) == 0 {
+		return fmt.Errorf("no %s found; run 'bd install' first", lockFileName)
+	}
+
+	var failures []string
+	for name, entry := range lock.Binaries {
+		sum, err := sha256File(filepath.Join(binDir, entry.FileName))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		if sum != entry.BinarySHA256 {
+			failures = append(failures, fmt.Sprintf("%s: checksum mismatch (want %s, got %s)", name, entry.BinarySHA256, sum))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d binaries failed verification:\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+
+	fmt.Printf("All %d binaries verified in %s\n", len(lock.Binaries), binDir)
+	return nil
+}
+
+const bundleBinPrefix = "bin"
+
+// defaultBundlePath picks a portable archive format per host: .tar.gz on
+// Unix (preserves symlinks), .zip on Windows.
+func defaultBundlePath() string {
+	if runtime.GOOS == "windows" {
+		return "bd-tools.zip"
+	}
+	return "bd-tools.tar.gz"
+}
+
+// extractionBinDir resolves the destination binDir for `bd bundle --extract`.
+// A bd.json may not exist yet on the consuming machine, so it falls back to
+// the default "bin" rather than requiring one up front.
+func extractionBinDir() string {
+	if config, _, err := loadConfig(); err == nil && config.BinDir != "" {
+		return config.BinDir
+	}
+	abs, err := filepath.Abs("bin")
+	if err != nil {
+		return "bin"
+	}
+	return abs
+}
+
+// createBundle packages binDir, bd.json and bd.lock into a single archive at
+// outPath. Entries are written in sorted order with zeroed timestamps and no
+// owner/uid/gid so the archive hashes identically across machines given the
+// same bd.lock.
+func createBundle(binDir, configDir, outPath string) error {
+	entries, err := os.ReadDir(binDir)
+	if err != nil {
+		return fmt.Errorf("read binDir: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", outPath, err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(outPath, ".zip") {
+		err = writeZipBundle(f, binDir, configDir, names)
+	} else {
+		err = writeTarGzBundle(f, binDir, configDir, names)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote bundle %s\n", outPath)
+	return nil
+}
+
+func writeTarGzBundle(f *os.File, binDir, configDir string, names []string) error {
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	if err := addRootFileToTar(tw, filepath.Join(configDir, configFileName), configFileName, true); err != nil {
+		return err
+	}
+	if err := addRootFileToTar(tw, filepath.Join(configDir, lockFileName), lockFileName, false); err != nil {
+		return err
 	}
 
-	fmt.Printf("Installed: %s %s\n", bin.Name, printVersion)
+	for _, name := range names {
+		entryPath := filepath.Join(binDir, name)
+		info, err := os.Lstat(entryPath)
+		if err != nil {
+			return fmt.Errorf("stat %s: %w", entryPath, err)
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(entryPath)
+			if err != nil {
+				return fmt.Errorf("readlink %s: %w", entryPath, err)
+			}
+			hdr := &tar.Header{
+				Name:     path.Join(bundleBinPrefix, name),
+				Typeflag: tar.TypeSymlink,
+				Linkname: filepath.Base(target),
+				Mode:     0755,
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return fmt.Errorf("write symlink header for %s: %w", name, err)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(entryPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", entryPath, err)
+		}
+		hdr := &tar.Header{
+			Name: path.Join(bundleBinPrefix, name),
+			Mode: 0755,
+			Size: int64(len(data)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write header for %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
 
 	return nil
 }
 
+// addRootFileToTar writes sourcePath into the archive at its root under
+// archiveName. Missing optional files (e.g. bd.lock before the first
+// install) are silently skipped.
+func addRootFileToTar(tw *tar.Writer, sourcePath, archiveName string, required bool) error {
+	data, err := os.ReadFile(sourcePath)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", sourcePath, err)
+	}
+
+	hdr := &tar.Header{Name: archiveName, Mode: 0644, Size: int64(len(data))}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write header for %s: %w", archiveName, err)
+	}
+	_, err = tw.Write(data)
+	return err
+}
+
+func writeZipBundle(f *os.File, binDir, configDir string, names []string) error {
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	if err := addRootFileToZip(zw, filepath.Join(configDir, configFileName), configFileName, true); err != nil {
+		return err
+	}
+	if err := addRootFileToZip(zw, filepath.Join(configDir, lockFileName), lockFileName, false); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		entryPath := filepath.Join(binDir, name)
+		data, err := os.ReadFile(entryPath)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", entryPath, err)
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: path.Join(bundleBinPrefix, name), Method: zip.Deflate})
+		if err != nil {
+			return fmt.Errorf("write header for %s: %w", name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func addRootFileToZip(zw *zip.Writer, sourcePath, archiveName string, required bool) error {
+	data, err := os.ReadFile(sourcePath)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read %s: %w", sourcePath, err)
+	}
+
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: archiveName, Method: zip.Deflate})
+	if err != nil {
+		return fmt.Errorf("write header for %s: %w", archiveName, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// extractBundle drops a bundle's binDir contents and bd.json/bd.lock into
+// place, the inverse of createBundle, so an air-gapped consumer can use a
+// pinned tool set without re-invoking go install.
+func extractBundle(archivePath, binDir string) error {
+	var err error
+	if strings.HasSuffix(archivePath, ".zip") {
+		err = extractZipBundle(archivePath, binDir)
+	} else {
+		err = extractTarGzBundle(archivePath, binDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Extracted %s into %s\n", archivePath, binDir)
+	return nil
+}
+
+func extractTarGzBundle(archivePath, binDir string) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", archivePath, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("open gzip reader: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %w", err)
+		}
+
+		destPath, err := bundleEntryDest(hdr.Name, binDir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(destPath), err)
+		}
+
+		if hdr.Typeflag == tar.TypeSymlink {
+			if filepath.IsAbs(hdr.Linkname) {
+				return fmt.Errorf("archive entry %q links outside the archive: %s", hdr.Name, hdr.Linkname)
+			}
+			if _, err := safeJoin(filepath.Dir(destPath), hdr.Linkname); err != nil {
+				return err
+			}
+			_ = os.Remove(destPath)
+			if err := os.Symlink(hdr.Linkname, destPath); err != nil {
+				return fmt.Errorf("create symlink %s: %w", destPath, err)
+			}
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", hdr.Name, err)
+		}
+		if err := os.WriteFile(destPath, data, 0755); err != nil {
+			return fmt.Errorf("write %s: %w", destPath, err)
+		}
+	}
+
+	return nil
+}
+
+func extractZipBundle(archivePath, binDir string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", archivePath, err)
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		destPath, err := bundleEntryDest(file.Name, binDir)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("create %s: %w", filepath.Dir(destPath), err)
+		}
+
+		if err := extractZipFile(file, destPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func extractZipFile(file *zip.File, destPath string) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("open %s: %w", file.Name, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", file.Name, err)
+	}
+
+	return os.WriteFile(destPath, data, 0755)
+}
+
+// bundleEntryDest maps an archive entry name back to a filesystem path:
+// entries under "bin/" land in binDir, everything else lands in cwd. Bundles
+// are meant to be shipped to and extracted on a different, possibly less
+// trusted machine than the one that produced them, so a crafted or corrupted
+// archive entry (e.g. "../../etc/cron.d/x") must not be allowed to resolve
+// outside its intended root.
+func bundleEntryDest(name, binDir string) (string, error) {
+	if rest, ok := strings.CutPrefix(name, bundleBinPrefix+"/"); ok {
+		return safeJoin(binDir, rest)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("determine working directory: %w", err)
+	}
+	return safeJoin(cwd, name)
+}
+
+// safeJoin joins rest onto root and rejects the result if it escapes root,
+// guarding archive extraction against zip-slip path traversal.
+func safeJoin(root, rest string) (string, error) {
+	root = filepath.Clean(root)
+	dest := filepath.Join(root, filepath.FromSlash(rest))
+	if dest != root && !strings.HasPrefix(dest, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry %q escapes %s", rest, root)
+	}
+	return dest, nil
+}
+
+func loadLockFile(configDir string) (*LockFile, error) {
+	lock := &LockFile{Binaries: map[string]*LockEntry{}}
+
+	data, err := os.ReadFile(filepath.Join(configDir, lockFileName))
+	if os.IsNotExist(err) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", lockFileName, err)
+	}
+
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", lockFileName, err)
+	}
+	if lock.Binaries == nil {
+		lock.Binaries = map[string]*LockEntry{}
+	}
+
+	return lock, nil
+}
+
+func saveLockFile(configDir string, lock *LockFile) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", lockFileName, err)
+	}
+	return os.WriteFile(filepath.Join(configDir, lockFileName), data, 0644)
+}
+
+// readModuleInfo extracts the resolved module version and go.sum-style hash
+// that the Go toolchain embedded in the binary's build info.
+func readModuleInfo(binPath string) (version, hash string, err error) {
+	out, err := exec.Command("go", "version", "-m", binPath).Output()
+	if err != nil {
+		return "", "", fmt.Errorf("go version -m %s: %w", binPath, err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		// A "mod" line is tab-separated as: mod, module path, version, h1 hash.
+		// fields[1] is the module path, not the version — take fields[2]/[3].
+		fields := strings.Split(strings.TrimPrefix(line, "\t"), "\t")
+		if len(fields) >= 4 && fields[0] == "mod" {
+			return fields[2], fields[3], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("module version not found in build info for %s", binPath)
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func symlinkBinary(target, link string) error {
 	if _, err := os.Stat(link); err == nil {
 		_ = os.Remove(link)
@@ -249,7 +975,11 @@ func symlinkBinary(target, link string) error {
 func execBinary(config *Config, binDir, name string, args []string) {
 	for _, bin := range config.Binaries {
 		if bin.Name == name {
-			binPath := filepath.Join(binDir, buildBinName(bin.Name, bin.Version, bin.Toolchain))
+			// installBinary always symlinks the host target to the
+			// un-suffixed name, regardless of whether Targets is set, so
+			// resolve through that symlink rather than reconstructing the
+			// (possibly goos/goarch-suffixed) versioned file name.
+			binPath := filepath.Join(binDir, buildSymlinkName(bin.Name))
 			if _, err := os.Stat(binPath); os.IsNotExist(err) {
 				die(fmt.Sprintf("Binary '%s' is not installed. Run 'bd install' first.", name))
 			}
@@ -268,6 +998,129 @@ func execCmd(binPath string, args []string) {
 	}
 }
 
+// shellCmd spawns the user's interactive shell with binDir on PATH, so every
+// tool in bd.json is directly invokable by name without the "bd exec" prefix.
+func shellCmd(binDir string) {
+	shellPath := os.Getenv("SHELL")
+	if runtime.GOOS == "windows" {
+		shellPath = os.Getenv("COMSPEC")
+	}
+	if shellPath == "" {
+		die("Failed to determine shell: $SHELL (or %COMSPEC% on Windows) is not set")
+	}
+
+	cmd := exec.Command(shellPath)
+	cmd.Env = buildShellEnv(binDir)
+	cmd.Stdout, cmd.Stderr, cmd.Stdin = os.Stdout, os.Stderr, os.Stdin
+	if err := cmd.Run(); err != nil {
+		die(fmt.Sprintf("Failed to run shell: %v", err))
+	}
+}
+
+// runCmd runs a single child process with binDir on PATH, the single-command
+// equivalent of shellCmd.
+func runCmd(binDir string, args []string) {
+	env := buildShellEnv(binDir)
+
+	// exec.Command resolves args[0] via LookPath against the current
+	// process's real PATH before cmd.Env (set below) ever takes effect, so a
+	// bd-managed tool that isn't already on the caller's ambient PATH would
+	// never be found. Resolve it against the constructed PATH ourselves.
+	binPath, err := lookPathIn(args[0], env)
+	if err != nil {
+		die(fmt.Sprintf("Failed to execute %s: %v", args[0], err))
+	}
+
+	cmd := exec.Command(binPath, args[1:]...)
+	cmd.Env = env
+	cmd.Stdout, cmd.Stderr, cmd.Stdin = os.Stdout, os.Stderr, os.Stdin
+	if err := cmd.Run(); err != nil {
+		die(fmt.Sprintf("Failed to execute %s: %v", args[0], err))
+	}
+}
+
+// pathEnvKey returns the PATH environment variable's name for the current
+// OS, since Windows env lookups are case-insensitive but conventionally
+// spelled "Path".
+func pathEnvKey() string {
+	if runtime.GOOS == "windows" {
+		return "Path"
+	}
+	return "PATH"
+}
+
+// lookPathIn resolves name against the PATH entry found in env, rather than
+// the current process's environment, by temporarily swapping PATH in before
+// delegating to exec.LookPath.
+func lookPathIn(name string, env []string) (string, error) {
+	var path string
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		if strings.EqualFold(key, pathEnvKey()) {
+			path = value
+			break
+		}
+	}
+
+	old, had := os.LookupEnv(pathEnvKey())
+	os.Setenv(pathEnvKey(), path)
+	defer func() {
+		if had {
+			os.Setenv(pathEnvKey(), old)
+		} else {
+			os.Unsetenv(pathEnvKey())
+		}
+	}()
+
+	return exec.LookPath(name)
+}
+
+// buildShellEnv copies the current environment, prepending binDir to PATH
+// (deduping any existing occurrence) and pointing GOBIN at binDir so
+// `go install`'d tools land directly where bd expects them.
+func buildShellEnv(binDir string) []string {
+	pathKey := pathEnvKey()
+	sep := string(os.PathListSeparator)
+
+	env := os.Environ()
+	result := make([]string, 0, len(env)+2)
+	sawPath, sawGobin := false, false
+
+	for _, e := range env {
+		key, value, _ := strings.Cut(e, "=")
+		switch {
+		case strings.EqualFold(key, pathKey):
+			result = append(result, pathKey+"="+prependToPath(value, binDir, sep))
+			sawPath = true
+		case key == "GOBIN":
+			result = append(result, "GOBIN="+binDir)
+			sawGobin = true
+		default:
+			result = append(result, e)
+		}
+	}
+	if !sawPath {
+		result = append(result, pathKey+"="+binDir)
+	}
+	if !sawGobin {
+		result = append(result, "GOBIN="+binDir)
+	}
+
+	return result
+}
+
+// prependToPath puts binDir first in path, removing any existing occurrence
+// so it isn't listed twice.
+func prependToPath(path, binDir, sep string) string {
+	entries := []string{binDir}
+	for _, entry := range strings.Split(path, sep) {
+		if entry != "" && entry != binDir {
+			entries = append(entries, entry)
+		}
+	}
+	return strings.Join(entries, sep)
+}
+
 func buildSymlinkName(name string) string {
 	if runtime.GOOS == "windows" {
 		name += ".exe"
@@ -275,9 +1128,20 @@ func buildSymlinkName(name string) string {
 	return name
 }
 
-func buildBinName(name, version, toolchain string) string {
-	binName := strings.Join([]string{name, version, toolchain}, "-")
-	if runtime.GOOS == "windows" {
+// buildBinName returns the versioned file name for a binary. When goos is
+// empty the result targets the host platform, matching the pre-cross-compile
+// naming; when set, the goos/goarch pair is appended so several targets of
+// the same binary and version can coexist in binDir.
+func buildBinName(name, version, toolchain, goos, goarch string) string {
+	parts := []string{name, version, toolchain}
+	targetGOOS := goos
+	if goos == "" {
+		targetGOOS = runtime.GOOS
+	} else {
+		parts = append(parts, goos, goarch)
+	}
+	binName := strings.Join(parts, "-")
+	if targetGOOS == "windows" {
 		binName += ".exe"
 	}
 	return binName